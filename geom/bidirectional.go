@@ -0,0 +1,180 @@
+package geom
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// side bundles one direction's search state together with the pointer
+// this direction's own expansion has produced for each closed Key, and
+// the node it searches toward (the other direction's root). Cross-side
+// lookups must go through byKey — a *Hex this side never expanded itself
+// isn't a valid key into its costs/cameFrom maps, even when it's
+// logically the same hex the other side closed.
+type side struct {
+	s      *searchState
+	byKey  map[Key]*Hex
+	target *Hex
+}
+
+// NavigateBidirectional finds a path from start to goal by running two
+// simultaneous A* searches — one forward from start, one backward from
+// goal — expanding whichever side has the cheaper top fScore and
+// terminating once the two open sets can no longer improve on the best
+// meeting point found so far. This is roughly 2× faster than a single A*
+// on average, and fails fast when the goal is behind an impassable
+// obstacle: the blocked side's open set empties long before a
+// one-directional search would have flooded the whole reachable region.
+// An optional maxCost caps how far either side is allowed to range; if
+// the two searches never meet within that budget, NavigateBidirectional
+// returns the same no-path error as an exhausted, unbudgeted search.
+func NavigateBidirectional(start, goal *Hex, obstacles []ContextualObstacle, edges []EdgeObstacle, maxCost ...float64) ([]*Hex, error) {
+	budget := math.Inf(1)
+	if len(maxCost) > 0 {
+		budget = maxCost[0]
+	}
+
+	oneStep := heuristic(&Hex{M: 0, N: 0}, &Hex{M: 0, N: 1})
+
+	fwdState := searchStatePool.Get().(*searchState)
+	fwdState.reset()
+	defer searchStatePool.Put(fwdState)
+
+	bwdState := searchStatePool.Get().(*searchState)
+	bwdState.reset()
+	defer searchStatePool.Put(bwdState)
+
+	fwdState.costs[start] = 0
+	fwdState.guesses[start] = heuristic(start, goal)
+	fwdStart := fwdState.newNode(start, fwdState.guesses[start])
+	heap.Push(&fwdState.open, fwdStart)
+	fwdState.nodes[start] = fwdStart
+
+	bwdState.costs[goal] = 0
+	bwdState.guesses[goal] = heuristic(goal, start)
+	bwdStart := bwdState.newNode(goal, bwdState.guesses[goal])
+	heap.Push(&bwdState.open, bwdStart)
+	bwdState.nodes[goal] = bwdStart
+
+	fwd := &side{s: fwdState, byKey: map[Key]*Hex{}, target: goal}
+	bwd := &side{s: bwdState, byKey: map[Key]*Hex{}, target: start}
+
+	mu := math.Inf(1)
+	var meetKey Key
+	haveMeet := false
+
+	// expand pops and relaxes the cheapest node on self, and checks
+	// whether it closes a Key the other side has already closed — a
+	// candidate meeting point. Cross-side cost/identity lookups go
+	// through byKey, never the raw *Hex pointer self just produced.
+	expand := func(self, other *side) {
+		current := heap.Pop(&self.s.open).(*pqNode).hex
+		delete(self.s.nodes, current)
+
+		key := Key{M: current.M, N: current.N}
+		self.s.closed[key] = struct{}{}
+		self.byKey[key] = current
+
+		if otherHex, ok := other.byKey[key]; ok {
+			if total := self.s.costs[current] + other.s.costs[otherHex]; total < mu {
+				mu = total
+				meetKey = key
+				haveMeet = true
+			}
+		}
+
+		for _, n := range current.Neighbors() {
+			if _, ok := self.s.closed[Key{M: n.M, N: n.N}]; ok {
+				continue
+			}
+
+			tentative := self.s.costs[current] + oneStep
+			for _, o := range obstacles {
+				if o.M == n.M && o.N == n.N {
+					if o.Cost == math.Inf(0) {
+						tentative = math.Inf(1)
+					} else {
+						tentative *= o.Cost
+					}
+					break
+				}
+			}
+
+			nKey := Key{M: n.M, N: n.N}
+			for _, e := range edges {
+				if (e.A == key && e.B == nKey) || (e.A == nKey && e.B == key) {
+					if e.Cost == math.Inf(0) {
+						tentative = math.Inf(1)
+					} else {
+						tentative *= e.Cost
+					}
+					break
+				}
+			}
+
+			// An impassable hex or edge must be skipped outright — Inf is
+			// never > an unbounded budget, so the budget check alone would
+			// still enqueue it and flood the open set.
+			if math.IsInf(tentative, 1) || tentative > budget {
+				continue
+			}
+
+			node, inOpen := self.s.nodes[n]
+			if inOpen && tentative >= self.s.costs[n] {
+				continue
+			}
+
+			self.s.cameFrom[n] = current
+			self.s.costs[n] = tentative
+			self.s.guesses[n] = tentative + heuristic(n, self.target)
+
+			if inOpen {
+				node.fScore = self.s.guesses[n]
+				heap.Fix(&self.s.open, node.index)
+			} else {
+				node = self.s.newNode(n, self.s.guesses[n])
+				heap.Push(&self.s.open, node)
+				self.s.nodes[n] = node
+			}
+		}
+	}
+
+	for fwd.s.open.Len() > 0 && bwd.s.open.Len() > 0 {
+		// The admissibility condition: once neither side's cheapest
+		// remaining node can possibly beat mu, the best meeting point
+		// found so far is optimal. Stopping on first intersection
+		// instead can miss a cheaper meeting point.
+		if fwd.s.open[0].fScore+bwd.s.open[0].fScore >= mu {
+			break
+		}
+
+		if fwd.s.open[0].fScore <= bwd.s.open[0].fScore {
+			expand(fwd, bwd)
+		} else {
+			expand(bwd, fwd)
+		}
+	}
+
+	if !haveMeet {
+		return nil, fmt.Errorf("no path available from %d,%d to %d,%d", start.M, start.N, goal.M, goal.N)
+	}
+
+	fwdHalf, err := reconstruct(fwd.s.cameFrom, fwd.byKey[meetKey])
+	if err != nil {
+		return nil, err
+	}
+	bwdHalf, err := reconstruct(bwd.s.cameFrom, bwd.byKey[meetKey])
+	if err != nil {
+		return nil, err
+	}
+
+	// bwdHalf runs goal -> ... -> meet; reverse it to meet -> ... -> goal
+	// and drop the duplicated meet hex before splicing onto fwdHalf.
+	for i := len(bwdHalf)/2 - 1; i >= 0; i-- {
+		opp := len(bwdHalf) - 1 - i
+		bwdHalf[i], bwdHalf[opp] = bwdHalf[opp], bwdHalf[i]
+	}
+
+	return append(fwdHalf, bwdHalf[1:]...), nil
+}
@@ -0,0 +1,46 @@
+package geom
+
+import "math"
+
+// EdgeObstacle taxes or blocks crossing the boundary between two adjacent
+// hexes, rather than entry into a hex. It models things a ContextualObstacle
+// can't: a wall or cliff between two tiles, or a river running along their
+// shared edge. A and B are order-independent — the edge between them is
+// looked up regardless of which side it's approached from. Cost multiplies
+// the normal traversal time the same way ContextualObstacle.Cost does, and
+// math.Inf(1) marks an impassable edge.
+//
+// When more than one EdgeObstacle matches the same A/B pair, the first
+// match in the slice wins, so place an override — e.g. from BridgeOver —
+// before the edge it overrides.
+type EdgeObstacle struct {
+	A, B Key
+
+	Cost float64
+}
+
+// WallBetween returns an EdgeObstacle that makes the edge between a and b
+// impassable, for modeling a wall or cliff.
+func WallBetween(a, b Key) EdgeObstacle {
+	return EdgeObstacle{A: a, B: b, Cost: math.Inf(1)}
+}
+
+// RiverLoop returns impassable edges connecting each key in the polyline
+// to the next, and the last back to the first, for modeling a river that
+// must be crossed via a bridge.
+func RiverLoop(keys []Key) []EdgeObstacle {
+	edges := make([]EdgeObstacle, 0, len(keys))
+	for i := range keys {
+		edges = append(edges, WallBetween(keys[i], keys[(i+1)%len(keys)]))
+	}
+	return edges
+}
+
+// BridgeOver returns a copy of edge with its Cost overridden, for
+// reopening passage where a wall or river would otherwise block it. Place
+// the result before the edge it overrides in the slice passed to Navigate
+// or Reachable, since the first matching edge wins.
+func BridgeOver(edge EdgeObstacle, cost float64) EdgeObstacle {
+	edge.Cost = cost
+	return edge
+}
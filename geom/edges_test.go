@@ -0,0 +1,98 @@
+package geom
+
+import "testing"
+
+func TestNavigateWallBetween(t *testing.T) {
+	grid := testGrid(-10, 10)
+	start := grid[Key{M: 0, N: 0}]
+	goal := grid[Key{M: 1, N: 0}]
+
+	unblocked, err := Navigate(start, goal, nil, nil)
+	if err != nil {
+		t.Fatalf("Navigate returned error on an open map: %v", err)
+	}
+	if len(unblocked) != 2 {
+		t.Fatalf("expected a direct 2-hex path on an open map, got %v", unblocked)
+	}
+
+	edges := []EdgeObstacle{WallBetween(Key{M: 0, N: 0}, Key{M: 1, N: 0})}
+	detour, err := Navigate(start, goal, nil, edges)
+	if err != nil {
+		t.Fatalf("Navigate should route around a wall, got error: %v", err)
+	}
+	if len(detour) <= len(unblocked) {
+		t.Errorf("walling off the direct edge should force a longer detour: direct len %d, detour len %d", len(unblocked), len(detour))
+	}
+
+	// The edge is order-independent: B,A should block exactly like A,B.
+	reversed := []EdgeObstacle{WallBetween(Key{M: 1, N: 0}, Key{M: 0, N: 0})}
+	detour2, err := Navigate(start, goal, nil, reversed)
+	if err != nil {
+		t.Fatalf("Navigate should route around a wall given in reverse order, got error: %v", err)
+	}
+	if len(detour2) <= len(unblocked) {
+		t.Errorf("reversed wall edge should also force a detour, got path len %d", len(detour2))
+	}
+}
+
+func TestRiverLoop(t *testing.T) {
+	keys := []Key{{M: 0, N: 0}, {M: 1, N: 0}, {M: 1, N: -1}}
+	edges := RiverLoop(keys)
+
+	want := []EdgeObstacle{
+		WallBetween(keys[0], keys[1]),
+		WallBetween(keys[1], keys[2]),
+		WallBetween(keys[2], keys[0]),
+	}
+	if len(edges) != len(want) {
+		t.Fatalf("expected %d edges (one per key in the loop), got %d", len(want), len(edges))
+	}
+	for i, e := range edges {
+		if e != want[i] {
+			t.Errorf("edge %d = %+v, want %+v", i, e, want[i])
+		}
+	}
+}
+
+func TestNavigateFullyWalledStartAndBridge(t *testing.T) {
+	grid := testGrid(-10, 10)
+	start := grid[Key{M: 0, N: 0}]
+	goal := grid[Key{M: 5, N: 0}]
+	startKey := Key{M: start.M, N: start.N}
+
+	var edges []EdgeObstacle
+	for _, n := range start.Neighbors() {
+		edges = append(edges, WallBetween(startKey, Key{M: n.M, N: n.N}))
+	}
+
+	if _, err := Navigate(start, goal, nil, edges); err == nil {
+		t.Fatal("expected no path once every edge leaving start is walled off")
+	}
+
+	// BridgeOver must be placed before the edge it overrides, since the
+	// first matching edge in the slice wins.
+	bridged := append([]EdgeObstacle{BridgeOver(edges[0], 1)}, edges...)
+	if _, err := Navigate(start, goal, nil, bridged); err != nil {
+		t.Fatalf("expected BridgeOver to reopen a path once one wall is bridged: %v", err)
+	}
+}
+
+func TestReachableImpassableEdge(t *testing.T) {
+	grid := testGrid(-10, 10)
+	start := grid[Key{M: 0, N: 0}]
+	oneStep := heuristic(&Hex{M: 0, N: 0}, &Hex{M: 0, N: 1})
+
+	withoutWall := Reachable(start, oneStep, nil, nil)
+	if _, ok := withoutWall[Key{M: 1, N: 0}]; !ok {
+		t.Fatal("(1,0) should be reachable at budget oneStep on an open map")
+	}
+
+	// Walling the direct edge forces any route to (1,0) through a detour
+	// costing at least 2*oneStep, which the oneStep budget can't cover —
+	// so (1,0) must drop out of the reachable set entirely.
+	edges := []EdgeObstacle{WallBetween(Key{M: 0, N: 0}, Key{M: 1, N: 0})}
+	withWall := Reachable(start, oneStep, nil, edges)
+	if _, ok := withWall[Key{M: 1, N: 0}]; ok {
+		t.Error("(1,0) should not be reachable within oneStep once the direct edge is walled off")
+	}
+}
@@ -0,0 +1,109 @@
+package geom
+
+import "testing"
+
+// BenchmarkNavigate128x128 exercises the heap-backed A* over a large,
+// obstacle-free grid to track allocations and pop cost as the open set
+// grows.
+func BenchmarkNavigate128x128(b *testing.B) {
+	const size = 128
+
+	grid := make(map[Key]*Hex, size*size)
+	for m := 0; m < size; m++ {
+		for n := 0; n < size; n++ {
+			grid[Key{M: m, N: n}] = &Hex{M: m, N: n}
+		}
+	}
+
+	start := grid[Key{M: 0, N: 0}]
+	goal := grid[Key{M: size - 1, N: size - 1}]
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Navigate(start, goal, nil, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// sparseObstacles scatters a deterministic, sparse set of costly hexes
+// across a size×size grid, standing in for the scattered cover/rubble a
+// real battlefield would have.
+func sparseObstacles(size int) []ContextualObstacle {
+	var obstacles []ContextualObstacle
+	for m := 0; m < size; m++ {
+		for n := 0; n < size; n++ {
+			if (m*31+n*17)%23 == 0 {
+				obstacles = append(obstacles, ContextualObstacle{M: m, N: n, Cost: 3})
+			}
+		}
+	}
+	return obstacles
+}
+
+// BenchmarkHierarchicalNavigate128x128 compares flat A* against the
+// HierarchicalGraph fast-path on a large map with sparse obstacles,
+// where the precomputed cluster abstraction should clearly win.
+func BenchmarkHierarchicalNavigate128x128(b *testing.B) {
+	const size = 128
+
+	grid := make([]*Hex, 0, size*size)
+	byKey := make(map[Key]*Hex, size*size)
+	for m := 0; m < size; m++ {
+		for n := 0; n < size; n++ {
+			h := &Hex{M: m, N: n}
+			grid = append(grid, h)
+			byKey[Key{M: m, N: n}] = h
+		}
+	}
+
+	obstacles := sparseObstacles(size)
+	start := byKey[Key{M: 0, N: 0}]
+	goal := byKey[Key{M: size - 1, N: size - 1}]
+
+	b.Run("Flat", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := Navigate(start, goal, obstacles, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Hierarchical", func(b *testing.B) {
+		hg := NewHierarchicalGraph(grid, 8, obstacles, nil)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := hg.Navigate(start, goal); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	// A moving obstacle: a building at (19,10) is destroyed mid-game, so
+	// only its cluster should need to be recomputed before the next
+	// Navigate call picks up the change.
+	b.Run("HierarchicalAfterInvalidate", func(b *testing.B) {
+		hg := NewHierarchicalGraph(grid, 8, obstacles, nil)
+		cleared := append([]ContextualObstacle(nil), obstacles...)
+		for i, o := range cleared {
+			if o.M == 19 && o.N == 10 {
+				cleared = append(cleared[:i], cleared[i+1:]...)
+				break
+			}
+		}
+		hg.UpdateObstacles(cleared, nil)
+		hg.Invalidate([]Key{{M: 19, N: 10}})
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := hg.Navigate(start, goal); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+// testGrid builds a (hi-lo)×(hi-lo) block of canonical Hex instances, with
+// M and N each running from lo to hi-1, the same
+// way the benchmarks do, so that Navigate's pointer-identity goal check
+// and Neighbors() both resolve against the same underlying hexes.
+func testGrid(lo, hi int) map[Key]*Hex {
+	grid := make(map[Key]*Hex, (hi-lo)*(hi-lo))
+	for m := lo; m < hi; m++ {
+		for n := lo; n < hi; n++ {
+			grid[Key{M: m, N: n}] = &Hex{M: m, N: n}
+		}
+	}
+	return grid
+}
+
+func TestNavigateBidirectional(t *testing.T) {
+	grid := testGrid(-10, 110)
+
+	tests := []struct {
+		name      string
+		start     Key
+		goal      Key
+		obstacles []ContextualObstacle
+	}{
+		{
+			name:  "unobstructed line",
+			start: Key{M: 0, N: 0},
+			goal:  Key{M: 5, N: 0},
+		},
+		{
+			name:  "longer unobstructed line",
+			start: Key{M: 0, N: 0},
+			goal:  Key{M: 100, N: 0},
+		},
+		{
+			name:  "diagonal",
+			start: Key{M: -3, N: -3},
+			goal:  Key{M: 4, N: 2},
+		},
+		{
+			name:  "costly midpoint",
+			start: Key{M: 0, N: 0},
+			goal:  Key{M: 6, N: 0},
+			obstacles: []ContextualObstacle{
+				{M: 3, N: 0, Cost: 5},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, goal := grid[tt.start], grid[tt.goal]
+
+			path, err := NavigateBidirectional(start, goal, tt.obstacles, nil)
+			if err != nil {
+				t.Fatalf("NavigateBidirectional returned error: %v", err)
+			}
+			if len(path) < 2 {
+				t.Fatalf("path too short to connect start and goal: %v", path)
+			}
+			if first := path[0]; first != start {
+				t.Errorf("path does not start at start: got %v, want %v", first, start)
+			}
+			if last := path[len(path)-1]; last != goal {
+				t.Errorf("path does not end at goal: got %v, want %v", last, goal)
+			}
+
+			want, err := Navigate(start, goal, tt.obstacles, nil)
+			if err != nil {
+				t.Fatalf("Navigate returned error: %v", err)
+			}
+			if len(path) != len(want) {
+				t.Errorf("NavigateBidirectional path length = %d, Navigate path length = %d", len(path), len(want))
+			}
+		})
+	}
+}
+
+func TestNavigateBidirectionalNoPath(t *testing.T) {
+	grid := testGrid(-5, 10)
+	start, goal := grid[Key{M: 0, N: 0}], grid[Key{M: 5, N: 0}]
+
+	inf := math.Inf(1)
+	obstacles := []ContextualObstacle{
+		{M: 1, N: -1, Cost: inf},
+		{M: 1, N: 0, Cost: inf},
+		{M: 0, N: 1, Cost: inf},
+		{M: -1, N: 1, Cost: inf},
+		{M: -1, N: 0, Cost: inf},
+		{M: 0, N: -1, Cost: inf},
+	}
+
+	if _, err := NavigateBidirectional(start, goal, obstacles, nil); err == nil {
+		t.Fatal("expected an error when start is completely walled in, got nil")
+	}
+}
@@ -0,0 +1,515 @@
+package geom
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// clusterID identifies one fixed-size partition of the hex map, in
+// cluster coordinates (a hex's M,N divided by the cluster size).
+type clusterID struct {
+	CM, CN int
+}
+
+// cluster is one partition of the map: the hexes it contains, the subset
+// of those hexes that border another cluster ("portals"), and the
+// precomputed intra-cluster A* distance between every pair of portals.
+type cluster struct {
+	id      clusterID
+	hexes   []*Hex
+	portals []*Hex
+	dist    map[Key]map[Key]float64
+}
+
+func (c *cluster) isPortal(k Key) bool {
+	for _, p := range c.portals {
+		if p.M == k.M && p.N == k.N {
+			return true
+		}
+	}
+	return false
+}
+
+// HierarchicalGraph abstracts a large hex map into fixed-size clusters
+// with precomputed intra-cluster portal distances, so a long-distance
+// Navigate doesn't have to run flat, tile-by-tile A* across the whole
+// map: it routes on a small abstract graph of portals instead, and only
+// refines the hex-level segments the abstract path actually crosses.
+type HierarchicalGraph struct {
+	clusterSize int
+	obstacles   []ContextualObstacle
+	edges       []EdgeObstacle
+	hexes       map[Key]*Hex
+	clusterOf   map[Key]clusterID
+	clusters    map[clusterID]*cluster
+
+	// portalGraph holds every precomputed edge between portals: intra-
+	// cluster distances plus single-step edges between adjacent portals
+	// in neighboring clusters.
+	portalGraph map[Key]map[Key]float64
+}
+
+// NewHierarchicalGraph partitions hexes into clusterSize×clusterSize
+// clusters and precomputes, for every cluster, the intra-cluster A*
+// distance between each pair of its boundary portal hexes.
+func NewHierarchicalGraph(hexes []*Hex, clusterSize int, obstacles []ContextualObstacle, edges []EdgeObstacle) *HierarchicalGraph {
+	g := &HierarchicalGraph{
+		clusterSize: clusterSize,
+		obstacles:   obstacles,
+		edges:       edges,
+		hexes:       map[Key]*Hex{},
+		clusterOf:   map[Key]clusterID{},
+		clusters:    map[clusterID]*cluster{},
+	}
+
+	for _, h := range hexes {
+		k := Key{M: h.M, N: h.N}
+		g.hexes[k] = h
+		id := g.clusterIDOf(h)
+		g.clusterOf[k] = id
+		c, ok := g.clusters[id]
+		if !ok {
+			c = &cluster{id: id}
+			g.clusters[id] = c
+		}
+		c.hexes = append(c.hexes, h)
+	}
+
+	for _, c := range g.clusters {
+		g.buildPortals(c)
+		g.buildDistances(c)
+	}
+	g.buildAbstractGraph()
+
+	return g
+}
+
+// UpdateObstacles replaces the obstacle and edge sets used for intra-
+// cluster pathing, e.g. when a building is destroyed or a bridge is
+// built. It's a cheap assignment on its own — call Invalidate afterwards
+// with the keys that actually changed so only the affected clusters (and
+// the abstract graph) are recomputed against the new sets.
+func (g *HierarchicalGraph) UpdateObstacles(obstacles []ContextualObstacle, edges []EdgeObstacle) {
+	g.obstacles = obstacles
+	g.edges = edges
+}
+
+func (g *HierarchicalGraph) clusterIDOf(h *Hex) clusterID {
+	return clusterID{CM: floorDiv(h.M, g.clusterSize), CN: floorDiv(h.N, g.clusterSize)}
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+func (g *HierarchicalGraph) clusterMembers(id clusterID) map[Key]interface{} {
+	c, ok := g.clusters[id]
+	if !ok {
+		return nil
+	}
+	members := make(map[Key]interface{}, len(c.hexes))
+	for _, h := range c.hexes {
+		members[Key{M: h.M, N: h.N}] = struct{}{}
+	}
+	return members
+}
+
+// buildPortals marks every hex in c with a neighbor outside of c as a
+// portal — the entry/exit points the abstract graph routes through.
+func (g *HierarchicalGraph) buildPortals(c *cluster) {
+	c.portals = nil
+	for _, h := range c.hexes {
+		for _, n := range h.Neighbors() {
+			nk := Key{M: n.M, N: n.N}
+			if _, ok := g.hexes[nk]; !ok {
+				continue
+			}
+			if g.clusterOf[nk] != c.id {
+				c.portals = append(c.portals, h)
+				break
+			}
+		}
+	}
+}
+
+// buildDistances runs a local A* between every pair of portals in c,
+// restricted to hexes belonging to c.
+func (g *HierarchicalGraph) buildDistances(c *cluster) {
+	c.dist = map[Key]map[Key]float64{}
+	members := g.clusterMembers(c.id)
+
+	for _, from := range c.portals {
+		fromKey := Key{M: from.M, N: from.N}
+		row := map[Key]float64{}
+		for _, to := range c.portals {
+			if from == to {
+				continue
+			}
+			path, err := navigateWithin(from, to, g.obstacles, g.edges, members)
+			if err != nil {
+				continue
+			}
+			row[Key{M: to.M, N: to.N}] = pathCost(path, g.obstacles, g.edges)
+		}
+		c.dist[fromKey] = row
+	}
+}
+
+// buildAbstractGraph flattens every cluster's precomputed intra-cluster
+// distances, plus a single oneStep edge between every pair of adjacent
+// portals in neighboring clusters, into one abstract adjacency.
+func (g *HierarchicalGraph) buildAbstractGraph() {
+	g.portalGraph = map[Key]map[Key]float64{}
+	add := func(a, b Key, cost float64) {
+		if g.portalGraph[a] == nil {
+			g.portalGraph[a] = map[Key]float64{}
+		}
+		if existing, ok := g.portalGraph[a][b]; !ok || cost < existing {
+			g.portalGraph[a][b] = cost
+		}
+	}
+
+	for _, c := range g.clusters {
+		for from, row := range c.dist {
+			for to, cost := range row {
+				add(from, to, cost)
+			}
+		}
+	}
+
+	oneStep := heuristic(&Hex{M: 0, N: 0}, &Hex{M: 0, N: 1})
+	for _, c := range g.clusters {
+		for _, p := range c.portals {
+			pk := Key{M: p.M, N: p.N}
+			for _, n := range p.Neighbors() {
+				nk := Key{M: n.M, N: n.N}
+				nc, ok := g.clusters[g.clusterOf[nk]]
+				if !ok || nc == c || !nc.isPortal(nk) {
+					continue
+				}
+
+				cost := oneStep
+				for _, o := range g.obstacles {
+					if o.M == n.M && o.N == n.N {
+						if o.Cost == math.Inf(0) {
+							cost = math.Inf(1)
+						} else {
+							cost *= o.Cost
+						}
+						break
+					}
+				}
+				for _, e := range g.edges {
+					if (e.A == pk && e.B == nk) || (e.A == nk && e.B == pk) {
+						if e.Cost == math.Inf(0) {
+							cost = math.Inf(1)
+						} else {
+							cost *= e.Cost
+						}
+						break
+					}
+				}
+				add(pk, nk, cost)
+			}
+		}
+	}
+}
+
+// Invalidate recomputes the portals and intra-cluster distances of every
+// cluster touched by keys, e.g. after a building is destroyed, and then
+// rebuilds the (cheap) abstract graph from the updated clusters. Call
+// UpdateObstacles first if the obstacle or edge set itself changed —
+// Invalidate only recomputes against whatever g.obstacles/g.edges
+// currently hold.
+func (g *HierarchicalGraph) Invalidate(keys []Key) {
+	touched := map[clusterID]interface{}{}
+	for _, k := range keys {
+		if id, ok := g.clusterOf[k]; ok {
+			touched[id] = struct{}{}
+		}
+	}
+
+	for id := range touched {
+		c, ok := g.clusters[id]
+		if !ok {
+			continue
+		}
+		g.buildPortals(c)
+		g.buildDistances(c)
+	}
+
+	g.buildAbstractGraph()
+}
+
+// Navigate finds a path from start to goal using the cluster
+// abstraction: it locates the start and goal clusters, runs A* over the
+// abstract graph of portals, and refines only the hex-level segments
+// inside the clusters the abstract path actually crosses.
+func (g *HierarchicalGraph) Navigate(start, goal *Hex) ([]*Hex, error) {
+	startID := g.clusterIDOf(start)
+	goalID := g.clusterIDOf(goal)
+
+	startCluster, ok := g.clusters[startID]
+	if !ok {
+		return nil, fmt.Errorf("no cluster found for start %d,%d", start.M, start.N)
+	}
+	goalCluster, ok := g.clusters[goalID]
+	if !ok {
+		return nil, fmt.Errorf("no cluster found for goal %d,%d", goal.M, goal.N)
+	}
+
+	if startID == goalID {
+		return navigateWithin(start, goal, g.obstacles, g.edges, g.clusterMembers(startID))
+	}
+
+	portals, err := g.abstractPath(start, startCluster, goal, goalCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	full := []*Hex{start}
+	prev := start
+	for _, k := range portals {
+		next := g.hexes[k]
+		if next == nil {
+			next = goal
+		}
+
+		var segment []*Hex
+		if members := g.clusterMembers(g.clusterOf[Key{M: prev.M, N: prev.N}]); members[Key{M: next.M, N: next.N}] != nil {
+			segment, err = navigateWithin(prev, next, g.obstacles, g.edges, members)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			// next is a portal in a neighboring cluster: buildAbstractGraph
+			// only ever links two portals this way when one is a direct
+			// Neighbors() hop from the other, so stitching is a single hex
+			// step, not a flat search across the whole map.
+			segment = []*Hex{prev, next}
+		}
+
+		full = append(full, segment[1:]...)
+		prev = next
+	}
+
+	return full, nil
+}
+
+// abstractPath runs Dijkstra over the portal graph, seeded with the real
+// cost from start to every portal of its own cluster. The last-mile local
+// distance from a candidate portal into goal varies per portal, so the
+// first one popped that can reach goal isn't necessarily the cheapest
+// overall — the search keeps going, tracking the best total cost seen,
+// until the next item popped can no longer beat it (the same
+// admissibility argument NavigateBidirectional uses). It returns the
+// sequence of portal Keys to refine between, with goal's Key appended
+// last.
+func (g *HierarchicalGraph) abstractPath(start *Hex, startCluster *cluster, goal *Hex, goalCluster *cluster) ([]Key, error) {
+	goalKey := Key{M: goal.M, N: goal.N}
+	startMembers := g.clusterMembers(startCluster.id)
+	goalMembers := g.clusterMembers(goalCluster.id)
+
+	costs := map[Key]float64{}
+	cameFrom := map[Key]Key{}
+	visited := map[Key]interface{}{}
+
+	pq := &dijkstraQueue{}
+	heap.Init(pq)
+
+	for _, p := range startCluster.portals {
+		path, err := navigateWithin(start, p, g.obstacles, g.edges, startMembers)
+		if err != nil {
+			continue
+		}
+		pk := Key{M: p.M, N: p.N}
+		cost := pathCost(path, g.obstacles, g.edges)
+		costs[pk] = cost
+		heap.Push(pq, &dijkstraNode{hex: p, cost: cost})
+	}
+
+	bestTotal := math.Inf(1)
+	var best []Key
+
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(*dijkstraNode)
+		if item.cost >= bestTotal {
+			break
+		}
+
+		k := Key{M: item.hex.M, N: item.hex.N}
+		if _, ok := visited[k]; ok {
+			continue
+		}
+		visited[k] = struct{}{}
+
+		if goalMembers[k] != nil || goalCluster.isPortal(k) {
+			if local, err := navigateWithin(g.hexes[k], goal, g.obstacles, g.edges, goalMembers); err == nil {
+				if total := item.cost + pathCost(local, g.obstacles, g.edges); total < bestTotal {
+					bestTotal = total
+					best = append(reconstructKeys(cameFrom, k), goalKey)
+				}
+			}
+		}
+
+		for nk, cost := range g.portalGraph[k] {
+			tentative := item.cost + cost
+			if c, ok := costs[nk]; ok && tentative >= c {
+				continue
+			}
+			costs[nk] = tentative
+			cameFrom[nk] = k
+			heap.Push(pq, &dijkstraNode{hex: g.hexes[nk], cost: tentative})
+		}
+	}
+
+	if best != nil {
+		return best, nil
+	}
+
+	return nil, fmt.Errorf("no abstract path available from %d,%d to %d,%d", start.M, start.N, goal.M, goal.N)
+}
+
+func reconstructKeys(cameFrom map[Key]Key, last Key) []Key {
+	result := []Key{last}
+	k, ok := cameFrom[last]
+	for ok {
+		result = append(result, k)
+		k, ok = cameFrom[k]
+	}
+	for i := len(result)/2 - 1; i >= 0; i-- {
+		opp := len(result) - 1 - i
+		result[i], result[opp] = result[opp], result[i]
+	}
+	return result
+}
+
+// navigateWithin runs A* from start to goal using only hexes present in
+// within, for computing intra-cluster portal distances.
+func navigateWithin(start, goal *Hex, obstacles []ContextualObstacle, edges []EdgeObstacle, within map[Key]interface{}) ([]*Hex, error) {
+	oneStep := heuristic(&Hex{M: 0, N: 0}, &Hex{M: 0, N: 1})
+
+	s := searchStatePool.Get().(*searchState)
+	s.reset()
+	defer searchStatePool.Put(s)
+
+	s.costs[start] = 0
+	s.guesses[start] = heuristic(start, goal)
+	startNode := s.newNode(start, s.guesses[start])
+	heap.Push(&s.open, startNode)
+	s.nodes[start] = startNode
+
+	for s.open.Len() > 0 {
+		current := heap.Pop(&s.open).(*pqNode).hex
+		delete(s.nodes, current)
+
+		if current.M == goal.M && current.N == goal.N {
+			return reconstruct(s.cameFrom, current)
+		}
+
+		curKey := Key{M: current.M, N: current.N}
+		s.closed[curKey] = struct{}{}
+
+		for _, n := range current.Neighbors() {
+			nk := Key{M: n.M, N: n.N}
+			if _, ok := within[nk]; !ok {
+				continue
+			}
+			if _, ok := s.closed[nk]; ok {
+				continue
+			}
+
+			tentative := s.costs[current] + oneStep
+			for _, o := range obstacles {
+				if o.M == n.M && o.N == n.N {
+					if o.Cost == math.Inf(0) {
+						tentative = math.Inf(1)
+					} else {
+						tentative *= o.Cost
+					}
+					break
+				}
+			}
+
+			for _, e := range edges {
+				if (e.A == curKey && e.B == nk) || (e.A == nk && e.B == curKey) {
+					if e.Cost == math.Inf(0) {
+						tentative = math.Inf(1)
+					} else {
+						tentative *= e.Cost
+					}
+					break
+				}
+			}
+
+			// An impassable hex or edge must be skipped outright, not just
+			// left to sort itself out in the priority queue, the same way
+			// Navigate and Reachable do.
+			if math.IsInf(tentative, 1) {
+				continue
+			}
+
+			node, inOpen := s.nodes[n]
+			if inOpen && tentative >= s.costs[n] {
+				continue
+			}
+
+			s.cameFrom[n] = current
+			s.costs[n] = tentative
+			s.guesses[n] = tentative + heuristic(n, goal)
+
+			if inOpen {
+				node.fScore = s.guesses[n]
+				heap.Fix(&s.open, node.index)
+			} else {
+				node = s.newNode(n, s.guesses[n])
+				heap.Push(&s.open, node)
+				s.nodes[n] = node
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no path available from %d,%d to %d,%d", start.M, start.N, goal.M, goal.N)
+}
+
+// pathCost totals the per-step cost of a path already found by A*. Each
+// step costs oneStep times the multiplier of the hex it enters and of the
+// edge it crosses. Unlike Navigate, which multiplies its whole running
+// cost by a multiplier, a multiplier here scales only its own step.
+func pathCost(path []*Hex, obstacles []ContextualObstacle, edges []EdgeObstacle) float64 {
+	oneStep := heuristic(&Hex{M: 0, N: 0}, &Hex{M: 0, N: 1})
+	var total float64
+	for i := 1; i < len(path); i++ {
+		step := oneStep
+		prev, n := path[i-1], path[i]
+		for _, o := range obstacles {
+			if o.M == n.M && o.N == n.N {
+				if o.Cost == math.Inf(0) {
+					return math.Inf(1)
+				}
+				step *= o.Cost
+				break
+			}
+		}
+
+		prevKey := Key{M: prev.M, N: prev.N}
+		nKey := Key{M: n.M, N: n.N}
+		for _, e := range edges {
+			if (e.A == prevKey && e.B == nKey) || (e.A == nKey && e.B == prevKey) {
+				if e.Cost == math.Inf(0) {
+					return math.Inf(1)
+				}
+				step *= e.Cost
+				break
+			}
+		}
+
+		total += step
+	}
+	return total
+}
@@ -0,0 +1,120 @@
+package geom
+
+import "testing"
+
+func buildGrid(lo, hi int) ([]*Hex, map[Key]*Hex) {
+	grid := make([]*Hex, 0, (hi-lo)*(hi-lo))
+	byKey := make(map[Key]*Hex, (hi-lo)*(hi-lo))
+	for m := lo; m < hi; m++ {
+		for n := lo; n < hi; n++ {
+			h := &Hex{M: m, N: n}
+			grid = append(grid, h)
+			byKey[Key{M: m, N: n}] = h
+		}
+	}
+	return grid, byKey
+}
+
+func TestHierarchicalGraphNavigateMatchesFlat(t *testing.T) {
+	grid, byKey := buildGrid(0, 32)
+
+	obstacles := []ContextualObstacle{
+		{M: 8, N: 8, Cost: 4},
+		{M: 16, N: 16, Cost: 4},
+	}
+
+	start := byKey[Key{M: 0, N: 0}]
+	goal := byKey[Key{M: 31, N: 31}]
+
+	hg := NewHierarchicalGraph(grid, 8, obstacles, nil)
+	hPath, err := hg.Navigate(start, goal)
+	if err != nil {
+		t.Fatalf("HierarchicalGraph.Navigate returned error: %v", err)
+	}
+	if first := hPath[0]; first != start {
+		t.Errorf("hierarchical path does not start at start: got %v", first)
+	}
+	if last := hPath[len(hPath)-1]; last != goal {
+		t.Errorf("hierarchical path does not end at goal: got %v", last)
+	}
+
+	flatPath, err := Navigate(start, goal, obstacles, nil)
+	if err != nil {
+		t.Fatalf("Navigate returned error: %v", err)
+	}
+
+	hCost := pathCost(hPath, obstacles, nil)
+	flatCost := pathCost(flatPath, obstacles, nil)
+	if hCost > flatCost*1.2 {
+		t.Errorf("hierarchical path cost %v is far worse than flat A* cost %v", hCost, flatCost)
+	}
+}
+
+func TestHierarchicalGraphBlockedByEdges(t *testing.T) {
+	grid, byKey := buildGrid(0, 16)
+
+	start := byKey[Key{M: 0, N: 0}]
+	goal := byKey[Key{M: 15, N: 0}]
+
+	flatPath, err := Navigate(start, goal, nil, nil)
+	if err != nil {
+		t.Fatalf("Navigate returned error: %v", err)
+	}
+
+	// Wall off every edge the flat path actually crosses. A hierarchical
+	// graph that ignores edges (as flat Navigate would without the edge
+	// set) would keep routing straight through; one that honors them must
+	// fail or take a different, still-valid path.
+	var edges []EdgeObstacle
+	for i := 1; i < len(flatPath); i++ {
+		a := Key{M: flatPath[i-1].M, N: flatPath[i-1].N}
+		b := Key{M: flatPath[i].M, N: flatPath[i].N}
+		edges = append(edges, WallBetween(a, b))
+	}
+
+	hg := NewHierarchicalGraph(grid, 8, nil, edges)
+	path, err := hg.Navigate(start, goal)
+	if err != nil {
+		// No alternate route exists on this small a grid; that's an
+		// acceptable outcome as long as it isn't the walled-off flat path.
+		return
+	}
+	for i := 1; i < len(path); i++ {
+		a := Key{M: path[i-1].M, N: path[i-1].N}
+		b := Key{M: path[i].M, N: path[i].N}
+		for _, e := range edges {
+			if (e.A == a && e.B == b) || (e.A == b && e.B == a) {
+				t.Errorf("hierarchical path crosses a walled edge %v-%v", a, b)
+			}
+		}
+	}
+}
+
+func TestHierarchicalGraphInvalidate(t *testing.T) {
+	grid, byKey := buildGrid(0, 16)
+
+	start := byKey[Key{M: 0, N: 0}]
+	goal := byKey[Key{M: 15, N: 0}]
+
+	obstacles := []ContextualObstacle{{M: 4, N: 0, Cost: 10}}
+	hg := NewHierarchicalGraph(grid, 8, obstacles, nil)
+
+	before, err := hg.Navigate(start, goal)
+	if err != nil {
+		t.Fatalf("Navigate returned error before invalidation: %v", err)
+	}
+	beforeCost := pathCost(before, obstacles, nil)
+
+	hg.UpdateObstacles(nil, nil)
+	hg.Invalidate([]Key{{M: 4, N: 0}})
+
+	after, err := hg.Navigate(start, goal)
+	if err != nil {
+		t.Fatalf("Navigate returned error after invalidation: %v", err)
+	}
+	afterCost := pathCost(after, nil, nil)
+
+	if afterCost >= beforeCost {
+		t.Errorf("clearing the obstacle and invalidating its cluster should lower path cost: before %v, after %v", beforeCost, afterCost)
+	}
+}
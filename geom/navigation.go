@@ -1,8 +1,10 @@
 package geom
 
 import (
+	"container/heap"
 	"fmt"
 	"math"
+	"sync"
 )
 
 // ContextualObstacle captures how much of an obstacle this is to the navigator.
@@ -16,6 +18,112 @@ type ContextualObstacle struct {
 	Cost float64
 }
 
+// pqNode is a single entry in an A* open-set priority queue, ordered by
+// fScore (the cost-so-far plus the heuristic estimate to the goal).
+type pqNode struct {
+	hex    *Hex
+	fScore float64
+	index  int
+}
+
+// priorityQueue implements container/heap over a slice of *pqNode so the
+// cheapest open node can be popped, and an existing node's position fixed
+// up after a decrease-key, in O(log N).
+type priorityQueue []*pqNode
+
+func (pq priorityQueue) Len() int { return len(pq) }
+
+func (pq priorityQueue) Less(i, j int) bool { return pq[i].fScore < pq[j].fScore }
+
+func (pq priorityQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+	pq[i].index = i
+	pq[j].index = j
+}
+
+func (pq *priorityQueue) Push(x interface{}) {
+	n := x.(*pqNode)
+	n.index = len(*pq)
+	*pq = append(*pq, n)
+}
+
+func (pq *priorityQueue) Pop() interface{} {
+	old := *pq
+	last := len(old) - 1
+	n := old[last]
+	old[last] = nil
+	n.index = -1
+	*pq = old[:last]
+	return n
+}
+
+// searchState holds the scratch structures a single A* search needs. It is
+// pooled across calls via sync.Pool so that back-to-back Navigate calls,
+// such as those driven by AI turns, don't churn the allocator with fresh
+// maps and slices every time. slab backs every pqNode a search pushes;
+// newNode hands out slab entries in order instead of allocating, so a
+// search re-using a warm searchState only allocates once it pushes more
+// nodes than any earlier search on it did; popped nodes are not reclaimed.
+type searchState struct {
+	open     priorityQueue
+	nodes    map[*Hex]*pqNode
+	closed   map[Key]interface{}
+	cameFrom map[*Hex]*Hex
+	costs    map[*Hex]float64
+	guesses  map[*Hex]float64
+	slab     []pqNode
+	slabLen  int
+}
+
+// reset clears a pooled searchState for reuse without freeing its
+// backing storage.
+func (s *searchState) reset() {
+	s.open = s.open[:0]
+	for k := range s.nodes {
+		delete(s.nodes, k)
+	}
+	for k := range s.closed {
+		delete(s.closed, k)
+	}
+	for k := range s.cameFrom {
+		delete(s.cameFrom, k)
+	}
+	for k := range s.costs {
+		delete(s.costs, k)
+	}
+	for k := range s.guesses {
+		delete(s.guesses, k)
+	}
+	s.slabLen = 0
+}
+
+// newNode hands out the next pqNode from slab, growing it only if the
+// current search has pushed more nodes than any previous search using
+// this searchState has.
+func (s *searchState) newNode(hex *Hex, fScore float64) *pqNode {
+	if s.slabLen < len(s.slab) {
+		n := &s.slab[s.slabLen]
+		s.slabLen++
+		*n = pqNode{hex: hex, fScore: fScore}
+		return n
+	}
+	s.slab = append(s.slab, pqNode{hex: hex, fScore: fScore})
+	s.slabLen = len(s.slab)
+	return &s.slab[s.slabLen-1]
+}
+
+var searchStatePool = sync.Pool{
+	New: func() interface{} {
+		return &searchState{
+			nodes:    map[*Hex]*pqNode{},
+			closed:   map[Key]interface{}{},
+			cameFrom: map[*Hex]*Hex{},
+			costs:    map[*Hex]float64{},
+			guesses:  map[*Hex]float64{},
+		}
+	},
+}
+
 func reconstruct(prevs map[*Hex]*Hex, current *Hex) ([]*Hex, error) {
 	result := []*Hex{current}
 	n, ok := prevs[current]
@@ -39,55 +147,60 @@ func heuristic(a, b *Hex) float64 {
 	return math.Pow(a.X()-b.X(), 2) + math.Pow(a.Y()-b.Y(), 2)
 }
 
-// Navigate a path from start to the goal, avoiding Impassable Hexes.
-func Navigate(start, goal *Hex, obstacles []ContextualObstacle) ([]*Hex, error) {
+// Navigate a path from start to the goal, avoiding Impassable Hexes and
+// edges. An optional maxCost caps how far the search is allowed to range;
+// if the goal can't be reached within that budget, Navigate returns the
+// best partial path instead of an error — the closed hex whose heuristic
+// distance to goal is smallest, i.e. the closest approach.
+func Navigate(start, goal *Hex, obstacles []ContextualObstacle, edges []EdgeObstacle, maxCost ...float64) ([]*Hex, error) {
+	budget := math.Inf(1)
+	if len(maxCost) > 0 {
+		budget = maxCost[0]
+	}
+
 	oneStep := heuristic(&Hex{M: 0, N: 0}, &Hex{M: 0, N: 1})
 
-	closed := map[Key]interface{}{}
-	open := map[*Hex]interface{}{
-		start: struct{}{},
-	}
-	cameFrom := map[*Hex]*Hex{}
-	costs := map[*Hex]float64{
-		start: 0,
-	}
-	guesses := map[*Hex]float64{
-		start: heuristic(start, goal),
-	}
+	s := searchStatePool.Get().(*searchState)
+	s.reset()
+	defer searchStatePool.Put(s)
+
+	s.costs[start] = 0
+	s.guesses[start] = heuristic(start, goal)
+	startNode := s.newNode(start, s.guesses[start])
+	heap.Push(&s.open, startNode)
+	s.nodes[start] = startNode
+
+	var best *Hex
+	bestH := math.Inf(1)
+
+	for s.open.Len() > 0 {
+		current := heap.Pop(&s.open).(*pqNode).hex
+		delete(s.nodes, current)
 
-	for len(open) > 0 {
-		var current *Hex
-		low := math.MaxFloat64
-		for k := range open {
-			if guesses[k] < low {
-				current = k
-				low = guesses[k]
-			}
-		}
 		if current == goal {
-			return reconstruct(cameFrom, current)
+			return reconstruct(s.cameFrom, current)
 		}
 
-		if current == nil {
-			break
-		}
+		s.closed[Key{M: current.M, N: current.N}] = struct{}{}
 
-		delete(open, current)
-		closed[Key{M: current.M, N: current.N}] = struct{}{}
+		if h := heuristic(current, goal); h < bestH {
+			best = current
+			bestH = h
+		}
 
 		for _, n := range current.Neighbors() {
-			if _, ok := closed[Key{M: n.M, N: n.N}]; ok {
+			if _, ok := s.closed[Key{M: n.M, N: n.N}]; ok {
 				continue
 			}
 
-			tentative := costs[current] + oneStep
+			tentative := s.costs[current] + oneStep
 
 			// The cost of passing through this hex might be affected by any
 			// obstacles occupying the Hex.
 			for _, o := range obstacles {
 				if o.M == n.M && o.N == n.N {
 					if o.Cost == math.Inf(0) {
-						tentative = math.MaxFloat64
+						tentative = math.Inf(1)
 					} else {
 						tentative *= o.Cost
 					}
@@ -95,64 +208,54 @@ func Navigate(start, goal *Hex, obstacles []ContextualObstacle) ([]*Hex, error)
 				}
 			}
 
-			if _, ok := open[n]; !ok {
-				open[n] = struct{}{}
-			} else if tentative >= costs[n] {
-				continue
+			// An edge obstacle sitting between current and n — a wall,
+			// cliff, or river — taxes or blocks crossing into n even when
+			// n itself is clear.
+			curKey := Key{M: current.M, N: current.N}
+			nKey := Key{M: n.M, N: n.N}
+			for _, e := range edges {
+				if (e.A == curKey && e.B == nKey) || (e.A == nKey && e.B == curKey) {
+					if e.Cost == math.Inf(0) {
+						tentative = math.Inf(1)
+					} else {
+						tentative *= e.Cost
+					}
+					break
+				}
 			}
 
-			cameFrom[n] = current
-			costs[n] = tentative
-			guesses[n] = costs[n] + heuristic(n, goal)
-		}
-	}
-	return nil, fmt.Errorf("no path available from %d,%d to %d,%d", start.M, start.N, goal.M, goal.N)
-}
-
-/*
-This Navigation does not support large characters that occupy more than one
-hex at a time.
-
-I think the interface should go from
-
-	func Navigate(start, goal *Hex, obstacles []ContextualObstacle) ([]*Hex, error) {
-
-to
-
-	func Navigate(start []*Hex, m, n int, obstacles []ContextualObstacle) ([]*Hex, error) {
-
-where start now accepts a slice of hexes that the character occupies, and m,n
-represent the goal by the number of hexes to offset the each starting hex by.
-
-I wonder what this means for detecting an M,N offset in terms of translating
-mouse coordinates?
-
-Potential issues:
-
-- We need to check whether any obstacle is only blocked by the character we
-are pathfinding *for*, and would not be an obstacle if the character was
-moving.
-
-Another option to explore would be to codify small, medium and large sized
-units, and have separate coordinate systems for each. This might be easier to
-implement side-by-side with the existing logic, i.e:
+			// An impassable hex or edge must be skipped outright, not just
+			// compared against budget — math.Inf(1) is never <= a finite
+			// budget, but it's also not > an unbounded (math.Inf(1)) one,
+			// so relying on the budget check alone would let the search
+			// flood straight through it.
+			if math.IsInf(tentative, 1) || tentative > budget {
+				continue
+			}
 
-	func Navigate(start, goal *Hex4, obstacles []ContextualObstacle) ([]*Hex4, error) {
-	func Navigate(start, goal *Hex7, obstacles []ContextualObstacle) ([]*Hex7, error) {
+			node, inOpen := s.nodes[n]
+			if inOpen && tentative >= s.costs[n] {
+				continue
+			}
 
-Where Hex4 is something like
+			s.cameFrom[n] = current
+			s.costs[n] = tentative
+			s.guesses[n] = tentative + heuristic(n, goal)
 
-type Hex4 struct {
-	O,P int
-	[]*Hex hexes
-	[]*Hex4 neighbors
-}
+			if inOpen {
+				node.fScore = s.guesses[n]
+				heap.Fix(&s.open, node.index)
+			} else {
+				node = s.newNode(n, s.guesses[n])
+				heap.Push(&s.open, node)
+				s.nodes[n] = node
+			}
+		}
+	}
 
-and Hex7 looks like
+	if !math.IsInf(budget, 1) && best != nil {
+		return reconstruct(s.cameFrom, best)
+	}
 
-type Hex7 struct {
-	Q,R int
-	[]*Hex hexes
-	[]*Hex7 neighbors
+	return nil, fmt.Errorf("no path available from %d,%d to %d,%d", start.M, start.N, goal.M, goal.N)
 }
-*/
\ No newline at end of file
@@ -0,0 +1,112 @@
+package geom
+
+import (
+	"container/heap"
+	"math"
+)
+
+// dijkstraNode is a single entry in a uniform-cost open set, ordered by
+// cost, with (M, N) as a deterministic tie-breaker so the expansion order
+// — and therefore the resulting Reachable set — is stable between calls.
+type dijkstraNode struct {
+	hex  *Hex
+	cost float64
+}
+
+type dijkstraQueue []*dijkstraNode
+
+func (q dijkstraQueue) Len() int { return len(q) }
+
+func (q dijkstraQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+	if q[i].hex.M != q[j].hex.M {
+		return q[i].hex.M < q[j].hex.M
+	}
+	return q[i].hex.N < q[j].hex.N
+}
+
+func (q dijkstraQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *dijkstraQueue) Push(x interface{}) { *q = append(*q, x.(*dijkstraNode)) }
+
+func (q *dijkstraQueue) Pop() interface{} {
+	old := *q
+	last := len(old) - 1
+	n := old[last]
+	old[last] = nil
+	*q = old[:last]
+	return n
+}
+
+// Reachable returns every Hex whose minimum cost from start is at most
+// budget, keyed by Key and mapped to that minimum cost. It runs a uniform
+// Dijkstra expansion (no heuristic, so it never early-exits on a single
+// goal) which is what a tactics-game UI needs to shade a unit's movement
+// range for a turn.
+func Reachable(start *Hex, budget float64, obstacles []ContextualObstacle, edges []EdgeObstacle) map[Key]float64 {
+	oneStep := heuristic(&Hex{M: 0, N: 0}, &Hex{M: 0, N: 1})
+
+	result := map[Key]float64{}
+	costs := map[Key]float64{{M: start.M, N: start.N}: 0}
+	visited := map[Key]interface{}{}
+
+	pq := &dijkstraQueue{{hex: start, cost: 0}}
+	heap.Init(pq)
+
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(*dijkstraNode)
+		k := Key{M: item.hex.M, N: item.hex.N}
+		if _, ok := visited[k]; ok {
+			continue
+		}
+		visited[k] = struct{}{}
+		result[k] = item.cost
+
+		for _, n := range item.hex.Neighbors() {
+			nk := Key{M: n.M, N: n.N}
+			if _, ok := visited[nk]; ok {
+				continue
+			}
+
+			tentative := item.cost + oneStep
+			for _, o := range obstacles {
+				if o.M == n.M && o.N == n.N {
+					if o.Cost == math.Inf(0) {
+						tentative = math.Inf(1)
+					} else {
+						tentative *= o.Cost
+					}
+					break
+				}
+			}
+
+			for _, e := range edges {
+				if (e.A == k && e.B == nk) || (e.A == nk && e.B == k) {
+					if e.Cost == math.Inf(0) {
+						tentative = math.Inf(1)
+					} else {
+						tentative *= e.Cost
+					}
+					break
+				}
+			}
+
+			// An impassable hex or edge must be skipped outright — Inf is
+			// never > an unbounded budget, so the budget check alone would
+			// still enqueue it and flood the open set.
+			if math.IsInf(tentative, 1) || tentative > budget {
+				continue
+			}
+
+			if c, ok := costs[nk]; ok && tentative >= c {
+				continue
+			}
+			costs[nk] = tentative
+			heap.Push(pq, &dijkstraNode{hex: n, cost: tentative})
+		}
+	}
+
+	return result
+}
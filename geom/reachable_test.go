@@ -0,0 +1,72 @@
+package geom
+
+import "testing"
+
+func TestReachable(t *testing.T) {
+	grid := testGrid(-10, 10)
+	start := grid[Key{M: 0, N: 0}]
+
+	// oneStep cost on an obstacle-free grid, derived the same way the
+	// package itself does, so the test doesn't hardcode a magic number.
+	oneStep := heuristic(&Hex{M: 0, N: 0}, &Hex{M: 0, N: 1})
+
+	result := Reachable(start, 2*oneStep, nil, nil)
+
+	if cost, ok := result[Key{M: 0, N: 0}]; !ok || cost != 0 {
+		t.Errorf("start should be reachable at cost 0, got %v (ok=%v)", cost, ok)
+	}
+	for _, n := range start.Neighbors() {
+		nk := Key{M: n.M, N: n.N}
+		if cost, ok := result[nk]; !ok || cost != oneStep {
+			t.Errorf("immediate neighbor %v should be reachable at cost %v, got %v (ok=%v)", nk, oneStep, cost, ok)
+		}
+	}
+
+	// A hex 3 steps out is beyond the 2*oneStep budget.
+	if _, ok := result[Key{M: 3, N: 0}]; ok {
+		t.Errorf("hex at distance 3 should not be reachable within budget %v", 2*oneStep)
+	}
+}
+
+func TestReachableObstacle(t *testing.T) {
+	grid := testGrid(-10, 10)
+	start := grid[Key{M: 0, N: 0}]
+
+	obstacles := []ContextualObstacle{{M: 1, N: 0, Cost: 10}}
+	oneStep := heuristic(&Hex{M: 0, N: 0}, &Hex{M: 0, N: 1})
+
+	result := Reachable(start, 5*oneStep, obstacles, nil)
+
+	if _, ok := result[Key{M: 1, N: 0}]; ok {
+		t.Errorf("hex with a 10x cost multiplier should be unreachable within a 5-step budget")
+	}
+	if _, ok := result[Key{M: -1, N: 0}]; !ok {
+		t.Errorf("hex on the unobstructed side should remain reachable")
+	}
+}
+
+func TestNavigatePartialPath(t *testing.T) {
+	grid := testGrid(-10, 10)
+	start := grid[Key{M: 0, N: 0}]
+	goal := grid[Key{M: 8, N: 0}]
+
+	oneStep := heuristic(&Hex{M: 0, N: 0}, &Hex{M: 0, N: 1})
+
+	path, err := Navigate(start, goal, nil, nil, 3*oneStep)
+	if err != nil {
+		t.Fatalf("Navigate with a budget too small to reach goal should return a partial path, got error: %v", err)
+	}
+	if len(path) == 0 {
+		t.Fatal("expected a non-empty partial path")
+	}
+	if first := path[0]; first != start {
+		t.Errorf("partial path does not start at start: got %v", first)
+	}
+	end := path[len(path)-1]
+	if end == goal {
+		t.Errorf("partial path should not reach goal when the budget is too small: got %v", end)
+	}
+	if h := heuristic(end, goal); h >= heuristic(start, goal) {
+		t.Errorf("partial path endpoint %v is no closer to goal than start, heuristic = %v", end, h)
+	}
+}
@@ -0,0 +1,149 @@
+package geom
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// Offset describes one cell of a multi-hex unit's footprint, relative to
+// the anchor hex the unit is considered to occupy.
+type Offset struct {
+	DM, DN int
+}
+
+// RectFootprint returns the Offsets covering a w×h rectangular footprint
+// anchored at its (0,0) corner.
+func RectFootprint(w, h int) []Offset {
+	offsets := make([]Offset, 0, w*h)
+	for dm := 0; dm < w; dm++ {
+		for dn := 0; dn < h; dn++ {
+			offsets = append(offsets, Offset{DM: dm, DN: dn})
+		}
+	}
+	return offsets
+}
+
+// HexRingFootprint returns the Offsets covering every hex within radius
+// (inclusive) of the anchor, in axial coordinates. A radius of 1 yields
+// the anchor plus its 6 neighbors, a common size-7 cluster footprint.
+func HexRingFootprint(radius int) []Offset {
+	offsets := make([]Offset, 0, 3*radius*(radius+1)+1)
+	for dm := -radius; dm <= radius; dm++ {
+		lo := maxInt(-radius, -dm-radius)
+		hi := minInt(radius, -dm+radius)
+		for dn := lo; dn <= hi; dn++ {
+			offsets = append(offsets, Offset{DM: dm, DN: dn})
+		}
+	}
+	return offsets
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// NavigateShape pathfinds a multi-hex unit whose occupied cells are given
+// by footprint, relative to an anchor hex, from start to the anchor
+// position (goalM, goalN). At each candidate anchor the algorithm tests
+// every footprint cell against obstacles and takes the highest cost
+// multiplier across the footprint (an Infinity anywhere in the footprint
+// blocks the whole move). When ignoreSelf is true, obstacles sitting
+// under the unit's own starting footprint are not treated as blocking it,
+// since the unit is not an obstacle to itself.
+func NavigateShape(footprint []Offset, start *Hex, goalM, goalN int, obstacles []ContextualObstacle, ignoreSelf bool) ([]*Hex, error) {
+	oneStep := heuristic(&Hex{M: 0, N: 0}, &Hex{M: 0, N: 1})
+	goal := &Hex{M: goalM, N: goalN}
+
+	self := map[Key]interface{}{}
+	if ignoreSelf {
+		for _, o := range footprint {
+			self[Key{M: start.M + o.DM, N: start.N + o.DN}] = struct{}{}
+		}
+	}
+
+	footprintCost := func(anchor *Hex) float64 {
+		mult := 1.0
+		for _, o := range footprint {
+			k := Key{M: anchor.M + o.DM, N: anchor.N + o.DN}
+			if _, ok := self[k]; ok {
+				continue
+			}
+			for _, ob := range obstacles {
+				if ob.M == k.M && ob.N == k.N {
+					if ob.Cost == math.Inf(0) {
+						return math.Inf(1)
+					}
+					if ob.Cost > mult {
+						mult = ob.Cost
+					}
+					break
+				}
+			}
+		}
+		return mult
+	}
+
+	s := searchStatePool.Get().(*searchState)
+	s.reset()
+	defer searchStatePool.Put(s)
+
+	s.costs[start] = 0
+	s.guesses[start] = heuristic(start, goal)
+	startNode := s.newNode(start, s.guesses[start])
+	heap.Push(&s.open, startNode)
+	s.nodes[start] = startNode
+
+	for s.open.Len() > 0 {
+		current := heap.Pop(&s.open).(*pqNode).hex
+		delete(s.nodes, current)
+
+		if current.M == goalM && current.N == goalN {
+			return reconstruct(s.cameFrom, current)
+		}
+
+		s.closed[Key{M: current.M, N: current.N}] = struct{}{}
+
+		for _, n := range current.Neighbors() {
+			if _, ok := s.closed[Key{M: n.M, N: n.N}]; ok {
+				continue
+			}
+
+			mult := footprintCost(n)
+			if math.IsInf(mult, 1) {
+				continue
+			}
+			tentative := s.costs[current] + oneStep*mult
+
+			node, inOpen := s.nodes[n]
+			if inOpen && tentative >= s.costs[n] {
+				continue
+			}
+
+			s.cameFrom[n] = current
+			s.costs[n] = tentative
+			s.guesses[n] = tentative + heuristic(n, goal)
+
+			if inOpen {
+				node.fScore = s.guesses[n]
+				heap.Fix(&s.open, node.index)
+			} else {
+				node = s.newNode(n, s.guesses[n])
+				heap.Push(&s.open, node)
+				s.nodes[n] = node
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no path available from %d,%d to %d,%d for footprint of size %d", start.M, start.N, goalM, goalN, len(footprint))
+}
@@ -0,0 +1,74 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNavigateShapeRectFootprint(t *testing.T) {
+	grid := testGrid(-10, 10)
+	start := grid[Key{M: 0, N: 0}]
+	footprint := RectFootprint(2, 2)
+
+	path, err := NavigateShape(footprint, start, 4, 0, nil, true)
+	if err != nil {
+		t.Fatalf("NavigateShape returned error: %v", err)
+	}
+	if len(path) == 0 {
+		t.Fatal("expected a non-empty path")
+	}
+	if last := path[len(path)-1]; last.M != 4 || last.N != 0 {
+		t.Errorf("path does not end at the anchor goal: got %v", last)
+	}
+}
+
+func TestNavigateShapeBlockedByFootprintObstacle(t *testing.T) {
+	grid := testGrid(-10, 10)
+	start := grid[Key{M: 0, N: 0}]
+	footprint := RectFootprint(2, 2)
+
+	// An obstacle under one of the footprint cells at the candidate anchor
+	// (3,0) — (3,0) and (4,0) would be covered by a 2x2 footprint anchored
+	// there — should block that anchor entirely, forcing a detour or
+	// failure rather than being silently ignored.
+	obstacles := []ContextualObstacle{{M: 4, N: 0, Cost: math.Inf(1)}}
+
+	if _, err := NavigateShape(footprint, start, 3, 0, obstacles, false); err == nil {
+		t.Fatal("expected an error when a footprint cell at the goal anchor is impassable")
+	}
+}
+
+func TestNavigateShapeIgnoreSelf(t *testing.T) {
+	grid := testGrid(-10, 10)
+	start := grid[Key{M: 0, N: 0}]
+	footprint := RectFootprint(2, 2)
+
+	// The obstacle sits under the unit's own starting footprint. Without
+	// ignoreSelf the adjacent anchors (1,-1), (1,0) and (0,-1) would all be
+	// blocked, since their footprints still cover that cell.
+	obstacles := []ContextualObstacle{{M: 1, N: 0, Cost: math.Inf(1)}}
+
+	if _, err := NavigateShape(footprint, start, 4, 0, obstacles, true); err != nil {
+		t.Fatalf("ignoreSelf should let the unit move despite an obstacle under its own footprint: %v", err)
+	}
+	// Anchoring at (1,0) plants the obstacle directly under the goal
+	// anchor's own footprint cell; without ignoreSelf that must block it.
+	if _, err := NavigateShape(footprint, start, 1, 0, obstacles, false); err == nil {
+		t.Fatal("without ignoreSelf the same obstacle should still block an anchor whose footprint covers it")
+	}
+}
+
+func TestHexRingFootprint(t *testing.T) {
+	ring := HexRingFootprint(1)
+	if len(ring) != 7 {
+		t.Errorf("HexRingFootprint(1) should cover the anchor plus its 6 neighbors (7 cells), got %d", len(ring))
+	}
+
+	seen := map[Offset]bool{}
+	for _, o := range ring {
+		seen[o] = true
+	}
+	if !seen[(Offset{})] {
+		t.Error("HexRingFootprint should include the anchor itself")
+	}
+}